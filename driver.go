@@ -0,0 +1,106 @@
+package redislock
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Driver 抽象了分布式锁实际用到的两个底层能力，
+// 让 Client/Lock 不再直接依赖某一个具体的 Redis 客户端实现。
+type Driver interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+	Subscribe(ctx context.Context, channel string) (Subscription, error)
+}
+
+// Subscription 代表一次频道订阅，Message 返回收到的消息内容
+type Subscription interface {
+	Message() <-chan string
+	Close() error
+}
+
+// configGetter 是一个可选能力：能读取 Redis 配置项的 Driver 可以实现它，
+// WatchLock 借助它检测 notify-keyspace-events 是否开启。
+type configGetter interface {
+	ConfigGet(ctx context.Context, key string) (string, error)
+}
+
+// goRedisDriver 是 NewClient 默认使用的 Driver 实现，基于 go-redis 的 Cmdable。
+// 它没有放进 driver/goredis 子包，是为了避免 redislock 反过来导入
+// redislock/driver/goredis（而后者又要导入 redislock 才能实现 Driver）造成的循环依赖；
+// driver/goredis 是同样逻辑的导出版本，供需要显式选择驱动的调用方使用。
+type goRedisDriver struct {
+	client redis.Cmdable
+}
+
+func newGoRedisDriver(client redis.Cmdable) *goRedisDriver {
+	return &goRedisDriver{client: client}
+}
+
+func (d *goRedisDriver) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return d.client.Eval(ctx, script, keys, args...).Result()
+}
+
+func (d *goRedisDriver) Subscribe(ctx context.Context, channel string) (Subscription, error) {
+	subscriber, ok := d.client.(keyspaceSubscriber)
+	if !ok {
+		return nil, errors.New("rlock: 底层客户端不支持 Subscribe")
+	}
+	return newGoRedisSubscription(subscriber.Subscribe(ctx, channel)), nil
+}
+
+func (d *goRedisDriver) ConfigGet(ctx context.Context, key string) (string, error) {
+	res, err := d.client.ConfigGet(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+	return res[key], nil
+}
+
+// keyspaceSubscriber 是 redis.Cmdable 之外的订阅能力（Cmdable 本身不包含 Subscribe），
+// go-redis 的 *redis.Client / *redis.ClusterClient 等具体实现都满足它。
+type keyspaceSubscriber interface {
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+type goRedisSubscription struct {
+	sub      *redis.PubSub
+	ch       chan string
+	done     chan struct{}
+	closeOne sync.Once
+}
+
+func newGoRedisSubscription(sub *redis.PubSub) *goRedisSubscription {
+	s := &goRedisSubscription{sub: sub, ch: make(chan string), done: make(chan struct{})}
+	go func() {
+		defer close(s.ch)
+		msgs := sub.Channel()
+		for {
+			select {
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				// Close() 之后不会再有人读 s.ch，不 select 上 done 的话，
+				// 这个 send 会永远阻塞，goroutine 就泄漏了
+				select {
+				case s.ch <- msg.Payload:
+				case <-s.done:
+					return
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+func (s *goRedisSubscription) Message() <-chan string { return s.ch }
+
+func (s *goRedisSubscription) Close() error {
+	s.closeOne.Do(func() { close(s.done) })
+	return s.sub.Close()
+}
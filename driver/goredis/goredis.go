@@ -0,0 +1,87 @@
+// Package goredis 把 github.com/redis/go-redis/v9 适配成 redislock.Driver，
+// 供需要显式选择驱动的调用方使用；NewClient(redis.Cmdable) 内部走的是一份
+// 等价但未导出的实现，以避免 redislock 包反过来导入本包造成循环依赖。
+package goredis
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"redislock"
+)
+
+// Driver 是 redislock.Driver 基于 go-redis Cmdable 的适配实现
+type Driver struct {
+	client redis.Cmdable
+}
+
+// New 用一个 go-redis 的 Cmdable（*redis.Client、*redis.ClusterClient 等）构造 Driver
+func New(client redis.Cmdable) *Driver {
+	return &Driver{client: client}
+}
+
+func (d *Driver) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return d.client.Eval(ctx, script, keys, args...).Result()
+}
+
+func (d *Driver) Subscribe(ctx context.Context, channel string) (redislock.Subscription, error) {
+	subscriber, ok := d.client.(interface {
+		Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+	})
+	if !ok {
+		return nil, errors.New("goredis: 底层客户端不支持 Subscribe")
+	}
+	return newSubscription(subscriber.Subscribe(ctx, channel)), nil
+}
+
+// ConfigGet 是一个可选能力，redislock.WatchLock 会用它检测 notify-keyspace-events
+func (d *Driver) ConfigGet(ctx context.Context, key string) (string, error) {
+	res, err := d.client.ConfigGet(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+	return res[key], nil
+}
+
+type subscription struct {
+	sub      *redis.PubSub
+	ch       chan string
+	done     chan struct{}
+	closeOne sync.Once
+}
+
+func newSubscription(sub *redis.PubSub) *subscription {
+	s := &subscription{sub: sub, ch: make(chan string), done: make(chan struct{})}
+	go func() {
+		defer close(s.ch)
+		msgs := sub.Channel()
+		for {
+			select {
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				// Close() 之后不会再有人读 s.ch，不 select 上 done 的话，
+				// 这个 send 会永远阻塞，goroutine 就泄漏了
+				select {
+				case s.ch <- msg.Payload:
+				case <-s.done:
+					return
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+func (s *subscription) Message() <-chan string { return s.ch }
+
+func (s *subscription) Close() error {
+	s.closeOne.Do(func() { close(s.done) })
+	return s.sub.Close()
+}
@@ -0,0 +1,97 @@
+// Package redigo 把 github.com/gomodule/redigo 适配成 redislock.Driver，
+// 让使用 redigo 连接池的调用方也可以使用 redislock，而不必切换到 go-redis。
+package redigo
+
+import (
+	"context"
+	"sync"
+
+	goredigo "github.com/gomodule/redigo/redis"
+
+	"redislock"
+)
+
+// Driver 是 redislock.Driver 基于 github.com/gomodule/redigo 连接池的适配实现
+type Driver struct {
+	pool *goredigo.Pool
+}
+
+// New 用一个 redigo 连接池构造 Driver
+func New(pool *goredigo.Pool) *Driver {
+	return &Driver{pool: pool}
+}
+
+func (d *Driver) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	evalArgs := make([]interface{}, 0, len(keys)+len(args)+2)
+	evalArgs = append(evalArgs, script, len(keys))
+	for _, key := range keys {
+		evalArgs = append(evalArgs, key)
+	}
+	evalArgs = append(evalArgs, args...)
+	reply, err := conn.Do("EVAL", evalArgs...)
+	if err != nil {
+		return nil, err
+	}
+	// redigo 把 RESP 的 bulk string（比如 lock.lua 里的 return "OK"）解码成 []byte，
+	// 而不是 string，这里统一转换成 string，这样才能和 go-redis 驱动的返回值对齐，
+	// 否则 lock.go 里 res == "OK" 这类比较永远为 false
+	if b, ok := reply.([]byte); ok {
+		return string(b), nil
+	}
+	return reply, nil
+}
+
+func (d *Driver) Subscribe(ctx context.Context, channel string) (redislock.Subscription, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	psc := &goredigo.PubSubConn{Conn: conn}
+	if err := psc.Subscribe(channel); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return newSubscription(psc), nil
+}
+
+type subscription struct {
+	psc      *goredigo.PubSubConn
+	ch       chan string
+	done     chan struct{}
+	closeOne sync.Once
+}
+
+func newSubscription(psc *goredigo.PubSubConn) *subscription {
+	s := &subscription{psc: psc, ch: make(chan string), done: make(chan struct{})}
+	go func() {
+		defer close(s.ch)
+		for {
+			switch v := s.psc.Receive().(type) {
+			case goredigo.Message:
+				// Close() 之后不会再有人读 s.ch，不 select 上 done 的话，
+				// 这个 send 会永远阻塞，goroutine 就泄漏了
+				select {
+				case s.ch <- string(v.Data):
+				case <-s.done:
+					return
+				}
+			case error:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+func (s *subscription) Message() <-chan string { return s.ch }
+
+func (s *subscription) Close() error {
+	s.closeOne.Do(func() { close(s.done) })
+	return s.psc.Close()
+}
@@ -0,0 +1,56 @@
+package redigo
+
+import (
+	"context"
+	"testing"
+
+	goredigo "github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeConn 是一个最小化的 goredigo.Conn 实现，只用于在没有真实 Redis 的情况下
+// 验证 Driver.Eval 对 EVAL 回包的解码逻辑
+type fakeConn struct {
+	reply interface{}
+	err   error
+}
+
+func (f *fakeConn) Close() error                      { return nil }
+func (f *fakeConn) Err() error                        { return nil }
+func (f *fakeConn) Send(string, ...interface{}) error { return nil }
+func (f *fakeConn) Flush() error                      { return nil }
+func (f *fakeConn) Receive() (interface{}, error)     { return nil, nil }
+func (f *fakeConn) Do(string, ...interface{}) (interface{}, error) {
+	return f.reply, f.err
+}
+
+func newTestPool(conn goredigo.Conn) *goredigo.Pool {
+	return &goredigo.Pool{
+		Dial: func() (goredigo.Conn, error) {
+			return conn, nil
+		},
+	}
+}
+
+func TestDriver_Eval_OK(t *testing.T) {
+	// lock.lua 在加锁成功时 return "OK"，redigo 会把这个 bulk string 解码成 []byte，
+	// Eval 需要把它转换回 string，否则调用方 res == "OK" 的比较永远为 false
+	d := New(newTestPool(&fakeConn{reply: []byte("OK")}))
+	res, err := d.Eval(context.Background(), "return 'OK'", []string{"key1"}, "val1", int64(1000))
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", res)
+}
+
+func TestDriver_Eval_Int64(t *testing.T) {
+	// unlock.lua / refresh.lua 返回的整型回包应该原样透传，而不是被误转换
+	d := New(newTestPool(&fakeConn{reply: int64(1)}))
+	res, err := d.Eval(context.Background(), "return 1", []string{"key1"}, "val1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res)
+}
+
+func TestDriver_Eval_Error(t *testing.T) {
+	d := New(newTestPool(&fakeConn{err: assert.AnError}))
+	_, err := d.Eval(context.Background(), "return 1", []string{"key1"})
+	assert.ErrorIs(t, err, assert.AnError)
+}
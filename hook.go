@@ -0,0 +1,61 @@
+package redislock
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Hook 让调用方观测加锁生命周期中的关键事件，典型实现是写 Prometheus 指标
+// 或者打日志；redislock/metrics 提供了一份开箱即用的 Prometheus 实现。
+type Hook interface {
+	OnLockAttempt(ctx context.Context, key string)
+	OnLockAcquired(ctx context.Context, key string, dur time.Duration)
+	OnLockFailed(ctx context.Context, key string, err error)
+	OnRefresh(ctx context.Context, key string, err error)
+	OnUnlock(ctx context.Context, key string, err error)
+}
+
+// noopHook 是 Client 的默认 Hook，什么都不做，避免到处做 nil 判断
+type noopHook struct{}
+
+func (noopHook) OnLockAttempt(context.Context, string)                 {}
+func (noopHook) OnLockAcquired(context.Context, string, time.Duration) {}
+func (noopHook) OnLockFailed(context.Context, string, error)           {}
+func (noopHook) OnRefresh(context.Context, string, error)              {}
+func (noopHook) OnUnlock(context.Context, string, error)               {}
+
+// WithHook 为 Client 设置 Hook，用于监控、埋点
+func WithHook(hook Hook) ClientOption {
+	return func(c *Client) {
+		c.hook = hook
+	}
+}
+
+// WithTracer 为 Client 设置 OpenTelemetry Tracer，默认使用 otel.Tracer("redislock")
+func WithTracer(tracer trace.Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// startSpan 是 Lock/TryLock/Refresh/UnLock 共用的埋点逻辑：开一个 span，
+// 并在返回的 finish 函数里根据 err 设置 span 状态
+func startSpan(ctx context.Context, tracer trace.Tracer, name, key string) (context.Context, func(err error)) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attribute.String("redislock.key", key)))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func defaultTracer() trace.Tracer {
+	return otel.Tracer("redislock")
+}
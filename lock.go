@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -25,35 +26,77 @@ var (
 )
 
 type Client struct {
-	client redis.Cmdable
+	driver Driver
 	g      singleflight.Group
 	valuer func() string
+	hook   Hook
+	tracer trace.Tracer
+}
+
+// ClientOption 用于定制 Client 的可选行为
+type ClientOption func(*Client)
+
+// WithValuer 允许调用方替换默认的 uuid 持有者标识，
+// 例如换成 per-goroutine 或者 per-request 的身份，以配合可重入加锁
+func WithValuer(valuer func() string) ClientOption {
+	return func(c *Client) {
+		c.valuer = valuer
+	}
 }
 
 type Lock struct {
-	client     redis.Cmdable
+	driver     Driver
 	key        string
 	val        string
 	expiration time.Duration
-	unlock     chan struct{}
+	watchdog   *Watchdog
+	hook       Hook
+	tracer     trace.Tracer
+}
+
+// NewClient 基于 go-redis 的 Cmdable 创建 Client，是 NewClientWithDriver 的一层
+// 薄封装，保留了原有签名以兼容已有调用方
+func NewClient(client redis.Cmdable, opts ...ClientOption) *Client {
+	return NewClientWithDriver(newGoRedisDriver(client), opts...)
 }
 
-func NewClient(client redis.Cmdable) *Client {
-	return &Client{
-		client: client,
+// NewClientWithDriver 基于任意 Driver 实现创建 Client，
+// 使调用方可以脱离 go-redis，换成自己的 Redis 客户端
+func NewClientWithDriver(driver Driver, opts ...ClientOption) *Client {
+	c := &Client{
+		driver: driver,
 		valuer: func() string {
 			return uuid.New().String()
 		},
+		hook:   noopHook{},
+		tracer: defaultTracer(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-func newLock(client redis.Cmdable, key, val string, expiration time.Duration) *Lock {
+func (c *Client) newLock(key, val string, expiration time.Duration) *Lock {
 	return &Lock{
-		client:     client,
+		driver:     c.driver,
 		key:        key,
 		val:        val,
 		expiration: expiration,
-		unlock:     make(chan struct{}, 1),
+		hook:       c.hook,
+		tracer:     c.tracer,
+	}
+}
+
+// asInt64 把 Driver.Eval 返回的 interface{} 转换成 Lua 脚本约定的整型返回值
+func asInt64(res interface{}) (int64, error) {
+	switch v := res.(type) {
+	case int64:
+		return v, nil
+	case nil:
+		return 0, redis.Nil
+	default:
+		return 0, fmt.Errorf("rlock: 非预期的返回值类型 %T", res)
 	}
 }
 
@@ -83,7 +126,11 @@ func (c *Client) SingleflightLock(ctx context.Context, key string, expiration ti
 // 使用timer 进行睡眠操作，interval 时间到达之后进行下一次循环，进行加锁操作
 
 func (c *Client) Lock(ctx context.Context, key string,
-	expiration time.Duration, retry RetryStrategy, timeout time.Duration) (*Lock, error) {
+	expiration time.Duration, retry RetryStrategy, timeout time.Duration) (l *Lock, err error) {
+	start := time.Now()
+	ctx, finish := startSpan(ctx, c.tracer, "redislock.Lock", key)
+	defer func() { finish(err) }()
+
 	val := c.valuer()
 	var timer *time.Timer
 	defer func() {
@@ -93,16 +140,19 @@ func (c *Client) Lock(ctx context.Context, key string,
 	}()
 
 	for {
+		c.hook.OnLockAttempt(ctx, key)
 		lctx, cancel := context.WithTimeout(ctx, timeout)
-		res, err := c.client.Eval(lctx, luaLock, []string{key}, val, expiration.Seconds()).Result()
+		res, err := c.driver.Eval(lctx, luaLock, []string{key}, val, expiration.Milliseconds())
 		cancel()
 		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
 			// 非超时错误，那么基本上代表遇到了一些不可挽回的场景，所以没太大必要继续尝试了
 			// 比如说 Redis server 崩了，或者 EOF 了
+			c.hook.OnLockFailed(ctx, key, err)
 			return nil, err
 		}
 		if res == "OK" {
-			return newLock(c.client, key, val, expiration), nil
+			c.hook.OnLockAcquired(ctx, key, time.Since(start))
+			return c.newLock(key, val, expiration), nil
 		}
 		interval, ok := retry.Next()
 		if !ok {
@@ -111,7 +161,9 @@ func (c *Client) Lock(ctx context.Context, key string,
 			} else {
 				err = fmt.Errorf("锁被人持有: %w", ErrFailedToPreemptLock)
 			}
-			return nil, fmt.Errorf("rlock: 重试机会耗尽，%w", err)
+			err = fmt.Errorf("rlock: 重试机会耗尽，%w", err)
+			c.hook.OnLockFailed(ctx, key, err)
+			return nil, err
 		}
 		if timer == nil {
 			timer = time.NewTimer(interval)
@@ -121,106 +173,86 @@ func (c *Client) Lock(ctx context.Context, key string,
 		select {
 		case <-timer.C:
 		case <-ctx.Done():
+			c.hook.OnLockFailed(ctx, key, ctx.Err())
 			return nil, ctx.Err()
 		}
 	}
 }
 
-// TryLock 尝试加锁,但不一定真的能拿到锁
-func (c *Client) TryLock(ctx context.Context, key string, expiration time.Duration) (*Lock, error) {
-	val := c.valuer()
-	res, err := c.client.SetNX(ctx, key, val, expiration).Result()
+// TryLock 尝试加锁,但不一定真的能拿到锁。
+// 如果 val 与锁当前持有者一致（可重入），会直接增加持有次数并续约，不会阻塞。
+func (c *Client) TryLock(ctx context.Context, key string, expiration time.Duration) (l *Lock, err error) {
+	start := time.Now()
+	ctx, finish := startSpan(ctx, c.tracer, "redislock.TryLock", key)
+	defer func() { finish(err) }()
 
+	c.hook.OnLockAttempt(ctx, key)
+	val := c.valuer()
+	res, err := c.driver.Eval(ctx, luaLock, []string{key}, val, expiration.Milliseconds())
 	if err != nil {
+		c.hook.OnLockFailed(ctx, key, err)
 		return nil, err
 	}
-	if !res {
+	if res != "OK" {
+		c.hook.OnLockFailed(ctx, key, ErrFailedToPreemptLock)
 		return nil, ErrFailedToPreemptLock
 	}
 
-	return newLock(c.client, key, val, expiration), nil
-
+	c.hook.OnLockAcquired(ctx, key, time.Since(start))
+	return c.newLock(key, val, expiration), nil
 }
 
-func (l *Lock) UnLock(ctx context.Context) error {
-
-	// 调用unlock方法，默认需要解锁，则不再自动续约
+func (l *Lock) UnLock(ctx context.Context) (err error) {
+	ctx, finish := startSpan(ctx, l.tracer, "redislock.UnLock", l.key)
 	defer func() {
-		l.unlock <- struct{}{}
-		close(l.unlock)
+		finish(err)
+		l.hook.OnUnlock(ctx, l.key, err)
 	}()
 
-	res, err := l.client.Eval(ctx, luaUnlock, []string{l.key}, l.val).Int64()
+	// 解锁前先停掉看门狗并等它退出，避免它在 key 被删除之后还继续续约
+	if l.watchdog != nil {
+		l.watchdog.Stop()
+	}
 
+	raw, err := l.driver.Eval(ctx, luaUnlock, []string{l.key}, l.val)
 	if errors.Is(err, redis.Nil) {
 		return ErrLockNotHold
 	}
-
 	if err != nil {
 		return err
 	}
-	// 要判断 res 是不是 1
-	if res == 0 {
-		// 这把锁不是你的，或者这个 key 不存在
+	res, err := asInt64(raw)
+	if err != nil {
+		return err
+	}
+	// -2 表示 key 已经不存在，-1 表示锁不是你的；
+	// 其余返回值是解锁之后剩余的持有次数（可重入场景下 >0 代表还被自己持有着）
+	if res == -2 || res == -1 {
 		return ErrLockNotHold
 	}
 	return nil
 }
 
-func (l *Lock) AutoRefresh(interval time.Duration, timeout time.Duration) error {
-	ticker := time.NewTicker(interval)
-	ch := make(chan struct{}, 1)
-
-	defer ticker.Stop()
-	defer close(ch)
-
-	for {
-		select {
-		case <-ch:
-
-			ctx, cancel := context.WithTimeout(context.Background(), timeout)
-			err := l.Refresh(ctx)
-			cancel()
-
-			if errors.Is(err, context.DeadlineExceeded) {
-				ch <- struct{}{}
-				continue
-			}
-			if err != nil {
-				return err
-			}
-		case <-ticker.C:
-
-			ctx, cancel := context.WithTimeout(context.Background(), timeout)
-			err := l.Refresh(ctx)
-			cancel()
-
-			if errors.Is(err, context.DeadlineExceeded) {
-				ch <- struct{}{}
-				continue
-			}
-
-			if err != nil {
-				return err
-			}
-		case <-l.unlock:
-			return nil
-		}
-	}
-}
+func (l *Lock) Refresh(ctx context.Context) (err error) {
+	ctx, finish := startSpan(ctx, l.tracer, "redislock.Refresh", l.key)
+	defer func() {
+		finish(err)
+		l.hook.OnRefresh(ctx, l.key, err)
+	}()
 
-func (l *Lock) Refresh(ctx context.Context) error {
-	res, err := l.client.Eval(ctx, luaRefresh, []string{l.key}, l.val, l.expiration).Int64()
+	raw, err := l.driver.Eval(ctx, luaRefresh, []string{l.key}, l.val, l.expiration.Milliseconds())
 	if errors.Is(err, redis.Nil) {
 		return ErrLockNotHold
 	}
-
 	if err != nil {
 		return err
 	}
-	// 要判断 res 是不是 1
-	if res == 0 {
-		// 这把锁不是你的，或者这个 key 不存在
+	res, err := asInt64(raw)
+	if err != nil {
+		return err
+	}
+	// -2 表示 key 已经不存在，-1 表示锁不是你的，只有返回 1 才代表续约成功
+	if res != 1 {
 		return ErrLockNotHold
 	}
 	return nil
@@ -1,4 +1,4 @@
-package redis_lock
+package redislock
 
 import (
 	"context"
@@ -6,7 +6,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
-	"redis-lock/mocks"
+	"redislock/mocks"
 	"testing"
 	"time"
 )
@@ -32,8 +32,8 @@ func TestClient_TryLock(t *testing.T) {
 			expiration: time.Minute,
 			mock: func() redis.Cmdable {
 				rdb := mocks.NewMockCmdable(ctrl)
-				res := redis.NewBoolResult(true, nil)
-				rdb.EXPECT().SetNX(gomock.Any(), "locked-key", gomock.Any(), time.Minute).
+				res := redis.NewCmdResult("OK", nil)
+				rdb.EXPECT().Eval(gomock.Any(), luaLock, []string{"locked-key"}, gomock.Any()).
 					Return(res)
 				return rdb
 			},
@@ -47,8 +47,8 @@ func TestClient_TryLock(t *testing.T) {
 			expiration: time.Minute,
 			mock: func() redis.Cmdable {
 				rdb := mocks.NewMockCmdable(ctrl)
-				res := redis.NewBoolResult(false, errors.New("网络错误"))
-				rdb.EXPECT().SetNX(gomock.Any(), "net work error", gomock.Any(), time.Minute).
+				res := redis.NewCmdResult(nil, errors.New("网络错误"))
+				rdb.EXPECT().Eval(gomock.Any(), luaLock, []string{"net work error"}, gomock.Any()).
 					Return(res)
 				return rdb
 			},
@@ -64,8 +64,8 @@ func TestClient_TryLock(t *testing.T) {
 			expiration: time.Minute,
 			mock: func() redis.Cmdable {
 				rdb := mocks.NewMockCmdable(ctrl)
-				res := redis.NewBoolResult(false, nil)
-				rdb.EXPECT().SetNX(gomock.Any(), "failed-key", gomock.Any(), time.Minute).
+				res := redis.NewCmdResult("", nil)
+				rdb.EXPECT().Eval(gomock.Any(), luaLock, []string{"failed-key"}, gomock.Any()).
 					Return(res)
 				return rdb
 			},
@@ -86,9 +86,92 @@ func TestClient_TryLock(t *testing.T) {
 			if err != nil {
 				return
 			}
-			assert.NotNil(t, l.client)
+			assert.NotNil(t, l.driver)
 			assert.Equal(t, tc.wantLock.key, l.key)
 			assert.NotEmpty(t, l.val)
 		})
 	}
 }
+
+// fakeHashEntry 按 script/lua/{lock,unlock,refresh}.lua 约定的 {owner, cnt} 语义，
+// 在内存里模拟同一个 key 的 hash 状态，用来在没有真实 Redis 的情况下
+// 验证可重入加锁/解锁在 Client 这一层的行为是否符合预期
+type fakeHashEntry struct {
+	owner string
+	cnt   int64
+}
+
+type fakeReentrantDriver struct {
+	entries map[string]*fakeHashEntry
+}
+
+func newFakeReentrantDriver() *fakeReentrantDriver {
+	return &fakeReentrantDriver{entries: make(map[string]*fakeHashEntry)}
+}
+
+func (d *fakeReentrantDriver) Eval(_ context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	key := keys[0]
+	val := args[0].(string)
+	switch script {
+	case luaLock:
+		e, ok := d.entries[key]
+		if !ok {
+			d.entries[key] = &fakeHashEntry{owner: val, cnt: 1}
+			return "OK", nil
+		}
+		if e.owner != val {
+			return int64(-1), nil // 模拟 PTTL，只要不是 "OK"，TryLock 就当作"锁被人持有"
+		}
+		e.cnt++
+		return "OK", nil
+	case luaUnlock:
+		e, ok := d.entries[key]
+		if !ok {
+			return int64(-2), nil
+		}
+		if e.owner != val {
+			return int64(-1), nil
+		}
+		e.cnt--
+		if e.cnt <= 0 {
+			delete(d.entries, key)
+		}
+		return e.cnt, nil
+	default:
+		return nil, errors.New("fakeReentrantDriver: 未知脚本")
+	}
+}
+
+func (d *fakeReentrantDriver) Subscribe(context.Context, string) (Subscription, error) {
+	return nil, errors.New("fakeReentrantDriver: 不支持 Subscribe")
+}
+
+// TestClient_TryLock_Reentrant 验证同一个持有者重复加锁只会增加持有次数，
+// 必须解锁同样的次数才会真正释放
+func TestClient_TryLock_Reentrant(t *testing.T) {
+	driver := newFakeReentrantDriver()
+	c := NewClientWithDriver(driver, WithValuer(func() string { return "owner-1" }))
+
+	l1, err := c.TryLock(context.Background(), "reentrant-key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), driver.entries["reentrant-key"].cnt)
+
+	l2, err := c.TryLock(context.Background(), "reentrant-key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), driver.entries["reentrant-key"].cnt)
+
+	// 别的持有者此时应该拿不到锁
+	other := NewClientWithDriver(driver, WithValuer(func() string { return "owner-2" }))
+	_, err = other.TryLock(context.Background(), "reentrant-key", time.Minute)
+	assert.ErrorIs(t, err, ErrFailedToPreemptLock)
+
+	assert.NoError(t, l1.UnLock(context.Background()))
+	assert.NotNil(t, driver.entries["reentrant-key"], "第一次解锁之后，持有次数还没归零，key 不应该被删除")
+	assert.Equal(t, int64(1), driver.entries["reentrant-key"].cnt)
+
+	assert.NoError(t, l2.UnLock(context.Background()))
+	assert.Nil(t, driver.entries["reentrant-key"], "持有次数归零之后，key 应该被删除")
+
+	// 两次都解锁之后，应该已经不再持有这个锁了
+	assert.ErrorIs(t, l2.UnLock(context.Background()), ErrLockNotHold)
+}
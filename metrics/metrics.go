@@ -0,0 +1,185 @@
+// Package metrics 提供一份开箱即用的 redislock.Hook 实现，
+// 把加锁、续约、解锁的关键指标暴露成 Prometheus 可采集的形式。
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"redislock"
+)
+
+var (
+	_ redislock.Hook       = (*Hook)(nil)
+	_ prometheus.Collector = (*Hook)(nil)
+)
+
+// Hook 按 key 前缀（第一个 ":" 之前的部分）对指标打标签，
+// 避免单个 key 产生过多的标签基数。
+type Hook struct {
+	attempts       *prometheus.CounterVec
+	acquired       *prometheus.CounterVec
+	failed         *prometheus.CounterVec
+	acquireLatency prometheus.Histogram
+	holdDuration   prometheus.Histogram
+	held           *prometheus.GaugeVec
+
+	mu        sync.Mutex
+	heldLocks map[string]*heldLock // key -> 持有状态，用计数支持可重入加锁
+}
+
+// heldLock 记录一个 key 当前的重入深度和第一次获取到的时间，
+// 只有深度归零（真正释放）才会更新 locks_held / hold_duration_seconds。
+type heldLock struct {
+	count int
+	since time.Time
+}
+
+// NewHook 创建一个 Prometheus Hook，namespace 会作为所有指标名的前缀
+func NewHook(namespace string) *Hook {
+	return &Hook{
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "redislock",
+			Name:      "attempts_total",
+			Help:      "加锁尝试次数，按 key 前缀统计",
+		}, []string{"key_prefix"}),
+		acquired: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "redislock",
+			Name:      "acquired_total",
+			Help:      "加锁成功次数，按 key 前缀统计",
+		}, []string{"key_prefix"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "redislock",
+			Name:      "failed_total",
+			Help:      "失败次数，按 key 前缀和失败原因统计",
+		}, []string{"key_prefix", "reason"}),
+		acquireLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "redislock",
+			Name:      "acquire_latency_seconds",
+			Help:      "加锁耗时分布",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		holdDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "redislock",
+			Name:      "hold_duration_seconds",
+			Help:      "锁从获取到释放的持有时长分布",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		held: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "redislock",
+			Name:      "locks_held",
+			Help:      "当前持有中的锁数量，按 key 前缀统计",
+		}, []string{"key_prefix"}),
+		heldLocks: make(map[string]*heldLock),
+	}
+}
+
+func keyPrefix(key string) string {
+	if idx := strings.IndexByte(key, ':'); idx >= 0 {
+		return key[:idx]
+	}
+	return key
+}
+
+func (h *Hook) Describe(ch chan<- *prometheus.Desc) {
+	h.attempts.Describe(ch)
+	h.acquired.Describe(ch)
+	h.failed.Describe(ch)
+	h.acquireLatency.Describe(ch)
+	h.holdDuration.Describe(ch)
+	h.held.Describe(ch)
+}
+
+func (h *Hook) Collect(ch chan<- prometheus.Metric) {
+	h.attempts.Collect(ch)
+	h.acquired.Collect(ch)
+	h.failed.Collect(ch)
+	h.acquireLatency.Collect(ch)
+	h.holdDuration.Collect(ch)
+	h.held.Collect(ch)
+}
+
+func (h *Hook) OnLockAttempt(_ context.Context, key string) {
+	h.attempts.WithLabelValues(keyPrefix(key)).Inc()
+}
+
+func (h *Hook) OnLockAcquired(_ context.Context, key string, dur time.Duration) {
+	prefix := keyPrefix(key)
+	h.acquired.WithLabelValues(prefix).Inc()
+	h.acquireLatency.Observe(dur.Seconds())
+
+	h.mu.Lock()
+	hl, ok := h.heldLocks[key]
+	if !ok {
+		hl = &heldLock{since: time.Now()}
+		h.heldLocks[key] = hl
+		h.held.WithLabelValues(prefix).Inc()
+	}
+	hl.count++
+	h.mu.Unlock()
+}
+
+func (h *Hook) OnLockFailed(_ context.Context, key string, err error) {
+	h.failed.WithLabelValues(keyPrefix(key), "lock:"+errReason(err)).Inc()
+}
+
+func (h *Hook) OnRefresh(_ context.Context, key string, err error) {
+	if err != nil {
+		h.failed.WithLabelValues(keyPrefix(key), "refresh:"+errReason(err)).Inc()
+	}
+}
+
+// OnUnlock 只在真正成功解锁（重入计数归零）时才调整 locks_held / hold_duration_seconds，
+// 失败的解锁不应该影响这两个指标，可重入场景下也不能提前把还在被持有的 key 记成已释放。
+func (h *Hook) OnUnlock(_ context.Context, key string, err error) {
+	prefix := keyPrefix(key)
+	if err != nil {
+		h.failed.WithLabelValues(prefix, "unlock:"+errReason(err)).Inc()
+		return
+	}
+
+	h.mu.Lock()
+	hl, ok := h.heldLocks[key]
+	fullyReleased := false
+	if ok {
+		hl.count--
+		if hl.count <= 0 {
+			delete(h.heldLocks, key)
+			fullyReleased = true
+		}
+	}
+	h.mu.Unlock()
+
+	if fullyReleased {
+		h.held.WithLabelValues(prefix).Dec()
+		h.holdDuration.Observe(time.Since(hl.since).Seconds())
+	}
+}
+
+// errReason 把错误归类成固定的几类标签值，避免把原始错误信息（里面常常带地址、
+// 超时时长等动态内容）直接当 Prometheus 标签用，导致标签基数失控。
+func errReason(err error) string {
+	switch {
+	case err == nil:
+		return "none"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, redislock.ErrFailedToPreemptLock):
+		return "lock_held"
+	case errors.Is(err, redislock.ErrLockNotHold):
+		return "not_owner"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"redislock"
+)
+
+func TestHook_LockLifecycle(t *testing.T) {
+	h := NewHook("test")
+
+	h.OnLockAttempt(context.Background(), "biz:order-1")
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.attempts.WithLabelValues("biz")))
+
+	h.OnLockAcquired(context.Background(), "biz:order-1", 10*time.Millisecond)
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.acquired.WithLabelValues("biz")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.held.WithLabelValues("biz")))
+
+	// 失败的解锁不应该影响 locks_held / hold_duration_seconds
+	h.OnUnlock(context.Background(), "biz:order-1", errors.New("网络错误"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.held.WithLabelValues("biz")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.failed.WithLabelValues("biz", "unlock:other")))
+
+	// 真正解锁成功之后才应该释放 locks_held
+	h.OnUnlock(context.Background(), "biz:order-1", nil)
+	assert.Equal(t, float64(0), testutil.ToFloat64(h.held.WithLabelValues("biz")))
+}
+
+// TestHook_OnUnlock_ReentrantDepth 验证可重入场景下，只有持有次数归零的那次
+// 解锁才会把 locks_held 减掉，中间的解锁不应该提前把 key 记成已释放
+func TestHook_OnUnlock_ReentrantDepth(t *testing.T) {
+	h := NewHook("test")
+
+	h.OnLockAcquired(context.Background(), "biz:order-1", time.Millisecond)
+	h.OnLockAcquired(context.Background(), "biz:order-1", time.Millisecond) // 重入一次
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.held.WithLabelValues("biz")), "重入加锁不应该让 locks_held 重复计数")
+
+	h.OnUnlock(context.Background(), "biz:order-1", nil)
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.held.WithLabelValues("biz")), "还有一次持有没有释放，locks_held 不应该减")
+
+	h.OnUnlock(context.Background(), "biz:order-1", nil)
+	assert.Equal(t, float64(0), testutil.ToFloat64(h.held.WithLabelValues("biz")))
+}
+
+func TestErrReason_BoundedCardinality(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{nil, "none"},
+		{context.DeadlineExceeded, "timeout"},
+		{redislock.ErrFailedToPreemptLock, "lock_held"},
+		{redislock.ErrLockNotHold, "not_owner"},
+		{errors.New("some raw error with an address 0xc0001"), "other"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, errReason(tc.err))
+	}
+}
+
+func TestKeyPrefix(t *testing.T) {
+	assert.Equal(t, "biz", keyPrefix("biz:order-1"))
+	assert.Equal(t, "no-prefix-key", keyPrefix("no-prefix-key"))
+}
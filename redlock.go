@@ -0,0 +1,190 @@
+package redislock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRedLockNotObtained 表示在大多数节点上加锁失败，即没有满足 Redlock 算法的法定人数要求
+var ErrRedLockNotObtained = errors.New("rlock: 未能在多数节点上取得锁")
+
+// clockDriftFactor 用于估算多节点间的时钟漂移，取 expiration 的 1%
+const clockDriftFactor = 0.01
+
+// RedLockClient 基于 Redlock 算法，在 N 个相互独立的 Redis 节点上加锁，
+// 只要半数以上节点加锁成功且总耗时没有超过锁的有效期，就认为加锁成功，
+// 从而容忍少数节点故障，而不是依赖单个 Redis 节点。
+type RedLockClient struct {
+	clients []redis.Cmdable
+	valuer  func() string
+}
+
+// RedLock 是 RedLockClient 加锁成功之后返回的锁句柄，Refresh/UnLock 都会向所有节点扇出。
+type RedLock struct {
+	clients    []redis.Cmdable
+	key        string
+	val        string
+	expiration time.Duration
+}
+
+// NewRedLockClient 创建一个 Redlock 客户端，clients 应当是相互独立的 Redis 节点
+func NewRedLockClient(clients ...redis.Cmdable) *RedLockClient {
+	return &RedLockClient{
+		clients: clients,
+		valuer: func() string {
+			return uuid.New().String()
+		},
+	}
+}
+
+func (r *RedLockClient) quorum() int {
+	return len(r.clients)/2 + 1
+}
+
+func (r *RedLockClient) drift(expiration time.Duration) time.Duration {
+	return time.Duration(float64(expiration)*clockDriftFactor) + 2*time.Millisecond
+}
+
+// TryLock 并发向所有节点发起加锁请求，只要成功节点数达到法定人数，
+// 且从发起请求到统计结果的耗时加上时钟漂移仍然小于 expiration，就视为加锁成功；
+// 否则会尽力向所有节点（包括没有及时应答的节点）发起解锁，避免残留部分节点的锁。
+func (r *RedLockClient) TryLock(ctx context.Context, key string, expiration time.Duration) (*RedLock, error) {
+	val := r.valuer()
+	start := time.Now()
+
+	perNodeTimeout := expiration / 3
+	if perNodeTimeout <= 0 {
+		perNodeTimeout = time.Second
+	}
+
+	successes := make([]bool, len(r.clients))
+	var wg sync.WaitGroup
+	for i, client := range r.clients {
+		wg.Add(1)
+		go func(i int, client redis.Cmdable) {
+			defer wg.Done()
+			lctx, cancel := context.WithTimeout(ctx, perNodeTimeout)
+			defer cancel()
+			res, err := client.Eval(lctx, luaLock, []string{key}, val, expiration.Milliseconds()).Result()
+			successes[i] = err == nil && res == "OK"
+		}(i, client)
+	}
+	wg.Wait()
+
+	success := 0
+	for _, ok := range successes {
+		if ok {
+			success++
+		}
+	}
+
+	lock := &RedLock{clients: r.clients, key: key, val: val, expiration: expiration}
+	elapsed := time.Since(start)
+	if success >= r.quorum() && elapsed < expiration-r.drift(expiration) {
+		return lock, nil
+	}
+	lock.bestEffortUnlock()
+	return nil, ErrRedLockNotObtained
+}
+
+// Lock 在 TryLock 失败时按照 retry 策略重试，用法与 Client.Lock 保持一致
+func (r *RedLockClient) Lock(ctx context.Context, key string,
+	expiration time.Duration, retry RetryStrategy, timeout time.Duration) (*RedLock, error) {
+	for {
+		lctx, cancel := context.WithTimeout(ctx, timeout)
+		l, err := r.TryLock(lctx, key, expiration)
+		cancel()
+		if err == nil {
+			return l, nil
+		}
+		if !errors.Is(err, ErrRedLockNotObtained) && !errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		interval, ok := retry.Next()
+		if !ok {
+			return nil, fmt.Errorf("rlock: 重试机会耗尽，%w", err)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// bestEffortUnlock 尽力向所有节点解锁，忽略单个节点的错误，不保证一定解锁成功
+func (l *RedLock) bestEffortUnlock() {
+	var wg sync.WaitGroup
+	for _, client := range l.clients {
+		wg.Add(1)
+		go func(client redis.Cmdable) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			_, _ = client.Eval(ctx, luaUnlock, []string{l.key}, l.val).Result()
+		}(client)
+	}
+	wg.Wait()
+}
+
+// UnLock 向所有节点扇出解锁请求，只要有半数以上节点确认解锁成功就返回 nil
+func (l *RedLock) UnLock(ctx context.Context) error {
+	successes := make([]bool, len(l.clients))
+	var wg sync.WaitGroup
+	for i, client := range l.clients {
+		wg.Add(1)
+		go func(i int, client redis.Cmdable) {
+			defer wg.Done()
+			res, err := client.Eval(ctx, luaUnlock, []string{l.key}, l.val).Int64()
+			// -2 表示 key 已经不存在，-1 表示锁不是自己的，其余返回值代表解锁成功；
+			// err != nil（超时、连接错误等）必须算失败，不能让 successes 的零值悄悄冒充成功
+			successes[i] = err == nil && res != -2 && res != -1
+		}(i, client)
+	}
+	wg.Wait()
+
+	success := 0
+	for _, ok := range successes {
+		if ok {
+			success++
+		}
+	}
+	if success < len(l.clients)/2+1 {
+		return ErrLockNotHold
+	}
+	return nil
+}
+
+// Refresh 向所有节点扇出续约请求，只要有半数以上节点续约成功就返回 nil
+func (l *RedLock) Refresh(ctx context.Context) error {
+	successes := make([]int64, len(l.clients))
+	var wg sync.WaitGroup
+	for i, client := range l.clients {
+		wg.Add(1)
+		go func(i int, client redis.Cmdable) {
+			defer wg.Done()
+			res, err := client.Eval(ctx, luaRefresh, []string{l.key}, l.val, l.expiration.Milliseconds()).Int64()
+			if err == nil {
+				successes[i] = res
+			}
+		}(i, client)
+	}
+	wg.Wait()
+
+	success := 0
+	for _, res := range successes {
+		if res == 1 {
+			success++
+		}
+	}
+	if success < len(l.clients)/2+1 {
+		return ErrLockNotHold
+	}
+	return nil
+}
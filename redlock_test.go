@@ -0,0 +1,117 @@
+package redislock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"redislock/mocks"
+)
+
+// TestRedLockClient_TryLock_Quorum 验证只要达到法定人数（N/2+1），即便有节点失败，
+// TryLock 也应该成功；达不到法定人数则应该失败，并且会向所有节点尽力解锁
+func TestRedLockClient_TryLock_Quorum(t *testing.T) {
+	testCases := []struct {
+		name    string
+		results []error // 每个节点 Eval 的结果，nil 表示该节点返回 "OK"
+		wantErr error
+	}{
+		{
+			name:    "all nodes succeed",
+			results: []error{nil, nil, nil},
+		},
+		{
+			name:    "quorum met despite one node failing",
+			results: []error{nil, nil, errors.New("网络错误")},
+		},
+		{
+			name:    "quorum not met",
+			results: []error{nil, errors.New("网络错误"), errors.New("网络错误")},
+			wantErr: ErrRedLockNotObtained,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clients := make([]redis.Cmdable, len(tc.results))
+			for i, nodeErr := range tc.results {
+				rdb := mocks.NewMockCmdable(ctrl)
+				if nodeErr == nil {
+					rdb.EXPECT().Eval(gomock.Any(), luaLock, []string{"redlock-key"}, gomock.Any()).
+						Return(redis.NewCmdResult("OK", nil))
+				} else {
+					rdb.EXPECT().Eval(gomock.Any(), luaLock, []string{"redlock-key"}, gomock.Any()).
+						Return(redis.NewCmdResult(nil, nodeErr))
+				}
+				// 加锁没有达到法定人数时，bestEffortUnlock 会向所有节点（包括失败的）发起解锁
+				if tc.wantErr != nil {
+					rdb.EXPECT().Eval(gomock.Any(), luaUnlock, []string{"redlock-key"}, gomock.Any()).
+						Return(redis.NewCmdResult(int64(-2), nil)).AnyTimes()
+				}
+				clients[i] = rdb
+			}
+
+			r := NewRedLockClient(clients...)
+			lock, err := r.TryLock(context.Background(), "redlock-key", time.Minute)
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr == nil {
+				assert.NotNil(t, lock)
+			}
+		})
+	}
+}
+
+// TestRedLock_UnLock_Quorum 验证 UnLock 只在半数以上节点确认解锁成功时才返回 nil，
+// 节点 Eval 出错不能被当成解锁成功（否则哪怕所有节点都联系不上，UnLock 也会"成功"）
+func TestRedLock_UnLock_Quorum(t *testing.T) {
+	testCases := []struct {
+		name    string
+		results []error
+		wantErr error
+	}{
+		{
+			name:    "all nodes succeed",
+			results: []error{nil, nil, nil},
+		},
+		{
+			name:    "quorum met despite one node erroring",
+			results: []error{nil, nil, errors.New("网络错误")},
+		},
+		{
+			name:    "all nodes error",
+			results: []error{errors.New("网络错误"), errors.New("网络错误"), errors.New("网络错误")},
+			wantErr: ErrLockNotHold,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			clients := make([]redis.Cmdable, len(tc.results))
+			for i, nodeErr := range tc.results {
+				rdb := mocks.NewMockCmdable(ctrl)
+				if nodeErr == nil {
+					rdb.EXPECT().Eval(gomock.Any(), luaUnlock, []string{"redlock-key"}, gomock.Any()).
+						Return(redis.NewCmdResult(int64(0), nil))
+				} else {
+					rdb.EXPECT().Eval(gomock.Any(), luaUnlock, []string{"redlock-key"}, gomock.Any()).
+						Return(redis.NewCmdResult(nil, nodeErr))
+				}
+				clients[i] = rdb
+			}
+
+			l := &RedLock{clients: clients, key: "redlock-key", val: "val"}
+			err := l.UnLock(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
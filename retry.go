@@ -0,0 +1,24 @@
+package redislock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RetryStrategy 定义了加锁重试的间隔策略
+type RetryStrategy interface {
+	// Next 返回下一次重试的间隔，如果不需要继续重试，第二个返回值为 false
+	Next() (time.Duration, bool)
+}
+
+// FixedIntervalRetryStrategy 固定间隔重试策略
+type FixedIntervalRetryStrategy struct {
+	Interval time.Duration
+	MaxCnt   int32
+	cnt      int32
+}
+
+func (f *FixedIntervalRetryStrategy) Next() (time.Duration, bool) {
+	cnt := atomic.AddInt32(&f.cnt, 1)
+	return f.Interval, cnt <= f.MaxCnt
+}
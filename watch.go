@@ -0,0 +1,127 @@
+package redislock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WatchLockConfig 控制 WatchLock 的行为
+type WatchLockConfig struct {
+	// db 用于拼接 keyspace 频道 __keyspace@<db>__:<key>
+	db int
+	// pollInterval 是两次轮询加锁之间的间隔；订阅成功时也会按它轮询，
+	// keyspace 通知不可用时它是唯一的重试节奏
+	pollInterval time.Duration
+	// skipNotifyCheck 为 true 时跳过 CONFIG GET notify-keyspace-events 检测，直接订阅
+	skipNotifyCheck bool
+}
+
+type WatchLockOption func(*WatchLockConfig)
+
+// WithWatchDB 指定 key 所在的逻辑库，用于拼接 keyspace 频道名
+func WithWatchDB(db int) WatchLockOption {
+	return func(cfg *WatchLockConfig) {
+		cfg.db = db
+	}
+}
+
+// WithWatchPollInterval 设置轮询加锁的间隔（默认 1s），订阅成功与否都会生效
+func WithWatchPollInterval(interval time.Duration) WatchLockOption {
+	return func(cfg *WatchLockConfig) {
+		cfg.pollInterval = interval
+	}
+}
+
+// WithSkipNotifyCheck 跳过 CONFIG GET notify-keyspace-events 检测，直接按开启处理
+func WithSkipNotifyCheck() WatchLockOption {
+	return func(cfg *WatchLockConfig) {
+		cfg.skipNotifyCheck = true
+	}
+}
+
+// WatchLock 是 Lock 的低延迟、低 QPS 替代方案：它不会像 Lock 那样持续轮询重试，
+// 而是先尝试加锁，失败后订阅 __keyspace@<db>__:<key> 的 del/expired 事件，
+// 收到这两个事件时会立刻再次尝试加锁。无论订阅是否成功，它还会每隔 pollInterval
+// 再尝试一次加锁；如果 Redis 没有开启 keyspace 通知或者订阅失效，就只剩下这种轮询。
+// 它不会根据锁剩余的 TTL 安排重试。
+func (c *Client) WatchLock(ctx context.Context, key string, expiration time.Duration, opts ...WatchLockOption) (*Lock, error) {
+	cfg := &WatchLockConfig{
+		pollInterval: time.Second,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	l, err := c.TryLock(ctx, key, expiration)
+	if err == nil {
+		return l, nil
+	}
+	if !errors.Is(err, ErrFailedToPreemptLock) {
+		return nil, err
+	}
+
+	var sub Subscription
+	if cfg.skipNotifyCheck || c.keyspaceNotificationsEnabled(ctx) {
+		channel := fmt.Sprintf("__keyspace@%d__:%s", cfg.db, key)
+		if s, subErr := c.driver.Subscribe(ctx, channel); subErr == nil {
+			sub = s
+			defer sub.Close()
+		}
+	}
+
+	ticker := time.NewTicker(cfg.pollInterval)
+	defer ticker.Stop()
+
+	var notifications <-chan string
+	if sub != nil {
+		notifications = sub.Message()
+	}
+
+	for {
+		l, err = c.TryLock(ctx, key, expiration)
+		if err == nil {
+			return l, nil
+		}
+		if !errors.Is(err, ErrFailedToPreemptLock) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case payload, ok := <-notifications:
+			if !ok {
+				// 订阅被关闭（连接断开、服务端重置等），notifications 永远不会再
+				// 阻塞，必须置为 nil 退出这个 case，退化成按 ticker 轮询，
+				// 否则 select 会一直立刻命中这个分支，变成无间隔的忙轮询
+				notifications = nil
+				continue
+			}
+			if payload != "del" && payload != "expired" {
+				continue
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// keyspaceNotificationsEnabled 通过 CONFIG GET 检测 Redis 是否开启了
+// 针对通用命令/过期事件的 keyspace 通知（K + g/x/e 中的任意一个）；
+// Driver 并不强制要求实现读取配置的能力，所以这里用可选接口做类型断言
+func (c *Client) keyspaceNotificationsEnabled(ctx context.Context) bool {
+	cg, ok := c.driver.(configGetter)
+	if !ok {
+		return false
+	}
+	flags, err := cg.ConfigGet(ctx, "notify-keyspace-events")
+	if err != nil {
+		return false
+	}
+	// A 只是 g$lshzxetd 这些事件类型的别名，并不包含 K，所以不能代替 K
+	hasKeyspace := strings.Contains(flags, "K")
+	hasRelevantEvent := strings.ContainsAny(flags, "gxe") || strings.Contains(flags, "A")
+	return hasKeyspace && hasRelevantEvent
+}
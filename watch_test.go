@@ -0,0 +1,56 @@
+package redislock
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeWatchDriver 是一个只为 TestClient_WatchLock_FallsBackToPollingWhenSubscriptionDies
+// 服务的最小 Driver 实现：锁永远拿不到，Subscribe 返回一个立刻失效的订阅
+type fakeWatchDriver struct {
+	evalCalls int32
+}
+
+func (d *fakeWatchDriver) Eval(context.Context, string, []string, ...interface{}) (interface{}, error) {
+	atomic.AddInt32(&d.evalCalls, 1)
+	return "", nil
+}
+
+func (d *fakeWatchDriver) Subscribe(context.Context, string) (Subscription, error) {
+	return &deadSubscription{ch: make(chan string)}, nil
+}
+
+// deadSubscription 模拟一个已经断开的订阅：Message() 返回的 channel 一开始就是关闭状态
+type deadSubscription struct {
+	ch chan string
+}
+
+func (s *deadSubscription) Message() <-chan string {
+	close(s.ch)
+	return s.ch
+}
+
+func (s *deadSubscription) Close() error { return nil }
+
+// TestClient_WatchLock_FallsBackToPollingWhenSubscriptionDies 验证订阅的 channel
+// 关闭之后，WatchLock 会退化成按 pollInterval 轮询，而不是变成一个没有间隔的忙轮询
+func TestClient_WatchLock_FallsBackToPollingWhenSubscriptionDies(t *testing.T) {
+	driver := &fakeWatchDriver{}
+	c := NewClientWithDriver(driver)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	_, err := c.WatchLock(ctx, "watch-key", time.Minute,
+		WithSkipNotifyCheck(), WithWatchPollInterval(20*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// 150ms 窗口、20ms 轮询间隔，正常情况下 TryLock 大约是十次；
+	// 如果退化成忙轮询，这个数字会是几万次
+	calls := atomic.LoadInt32(&driver.evalCalls)
+	assert.Less(t, calls, int32(30), "订阅失效后不应该变成无间隔的忙轮询")
+}
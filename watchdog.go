@@ -0,0 +1,151 @@
+package redislock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	watchdogInitialBackoff = 100 * time.Millisecond
+	watchdogMaxBackoff     = 10 * time.Second
+)
+
+// ErrorPolicy 控制 Watchdog.Errors() 在没有被及时消费时的行为
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyDropOldest 在 Errors() 的缓冲区满时丢弃最旧的错误，保留最新的一个
+	ErrorPolicyDropOldest ErrorPolicy = iota
+	// ErrorPolicyDropNewest 在 Errors() 的缓冲区满时直接丢弃新产生的错误，保留最旧的一个
+	ErrorPolicyDropNewest
+)
+
+// WatchdogOption 用于定制 Watchdog 的可选行为
+type WatchdogOption func(*Watchdog)
+
+// WithErrorPolicy 设置 Errors() 在消费者来不及处理时的丢弃策略，默认 ErrorPolicyDropOldest
+func WithErrorPolicy(policy ErrorPolicy) WatchdogOption {
+	return func(w *Watchdog) {
+		w.errPolicy = policy
+	}
+}
+
+// Watchdog 是 Lock 的自动续约后台任务句柄，取代了旧的阻塞式 AutoRefresh：
+// 续约在独立的 goroutine 里运行，调用方通过 Errors()/Done() 观测状态，
+// 而不必自己占用一个 goroutine 来跑续约循环。
+type Watchdog struct {
+	lock      *Lock
+	errs      chan error
+	done      chan struct{}
+	cancel    context.CancelFunc
+	stopOnce  sync.Once
+	errPolicy ErrorPolicy
+}
+
+// StartWatchdog 启动后台续约循环，每隔 interval 续约一次，单次续约的超时时间为 timeout
+func (l *Lock) StartWatchdog(interval, timeout time.Duration, opts ...WatchdogOption) *Watchdog {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watchdog{
+		lock:   l,
+		errs:   make(chan error, 1),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	l.watchdog = w
+	go w.run(ctx, interval, timeout)
+	return w
+}
+
+func (w *Watchdog) run(ctx context.Context, interval, timeout time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	backoff := watchdogInitialBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !w.refreshUntilOK(ctx, timeout, &backoff) {
+				return
+			}
+			backoff = watchdogInitialBackoff
+		}
+	}
+}
+
+// refreshUntilOK 续约一次；如果遇到超时，就按指数退避反复重试，直到续约成功、
+// 遇到非超时的终止性错误，或者 ctx 被取消为止，而不是放着 backoff 不用、
+// 干等下一个 ticker.C 周期。返回 false 表示续约循环应当彻底退出
+func (w *Watchdog) refreshUntilOK(ctx context.Context, timeout time.Duration, backoff *time.Duration) bool {
+	for {
+		rctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := w.lock.Refresh(rctx)
+		cancel()
+
+		if err == nil {
+			return true
+		}
+
+		w.emit(err)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			// 非超时错误基本意味着锁已经丢了，没有继续续约的必要
+			return false
+		}
+
+		select {
+		case <-time.After(*backoff):
+		case <-ctx.Done():
+			return false
+		}
+		*backoff *= 2
+		if *backoff > watchdogMaxBackoff {
+			*backoff = watchdogMaxBackoff
+		}
+	}
+}
+
+// emit 把 err 非阻塞地投递到 Errors()，缓冲区满时按 errPolicy 决定丢弃哪一个
+func (w *Watchdog) emit(err error) {
+	select {
+	case w.errs <- err:
+		return
+	default:
+	}
+	if w.errPolicy == ErrorPolicyDropNewest {
+		return
+	}
+	select {
+	case <-w.errs:
+	default:
+	}
+	select {
+	case w.errs <- err:
+	default:
+	}
+}
+
+// Errors 返回续约失败时投递错误的只读 channel，投递是非阻塞的
+func (w *Watchdog) Errors() <-chan error {
+	return w.errs
+}
+
+// Done 在续约循环退出后关闭
+func (w *Watchdog) Done() <-chan struct{} {
+	return w.done
+}
+
+// Stop 停止续约循环并等待其退出，可重复调用
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() {
+		w.cancel()
+	})
+	<-w.done
+}
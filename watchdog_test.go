@@ -0,0 +1,79 @@
+package redislock
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"redislock/mocks"
+)
+
+// TestWatchdog_BackoffRetriesBeforeNextTick 验证续约超时之后，Watchdog 会在
+// backoff 到期后立刻重试，而不是傻等下一个 interval 周期
+func TestWatchdog_BackoffRetriesBeforeNextTick(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls int32
+	rdb := mocks.NewMockCmdable(ctrl)
+	rdb.EXPECT().Eval(gomock.Any(), luaRefresh, []string{"watch-key"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _ []string, _ ...interface{}) *redis.Cmd {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return redis.NewCmdResult(nil, context.DeadlineExceeded)
+			}
+			return redis.NewCmdResult(int64(1), nil)
+		}).
+		AnyTimes()
+
+	c := NewClient(rdb)
+	l := c.newLock("watch-key", "val", time.Minute)
+
+	// interval 比 backoff 大得多：如果退避没有真正触发重试，第二次 Refresh
+	// 只能等到下一个 interval 才会发生，下面的 Eventually 就会超时
+	w := l.StartWatchdog(500*time.Millisecond, time.Second)
+	defer w.Stop()
+
+	select {
+	case err := <-w.Errors():
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(2 * time.Second):
+		t.Fatal("超时错误没有被投递到 Errors()")
+	}
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) >= 2
+	}, 300*time.Millisecond, 10*time.Millisecond, "backoff 到期后应该立刻重试，而不是等下一个 interval")
+}
+
+// TestWatchdog_TerminalErrorStopsRun 验证非超时错误会终止续约循环
+func TestWatchdog_TerminalErrorStopsRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rdb := mocks.NewMockCmdable(ctrl)
+	rdb.EXPECT().Eval(gomock.Any(), luaRefresh, []string{"watch-key"}, gomock.Any(), gomock.Any()).
+		Return(redis.NewCmdResult(int64(-1), nil)).
+		AnyTimes()
+
+	c := NewClient(rdb)
+	l := c.newLock("watch-key", "val", time.Minute)
+
+	w := l.StartWatchdog(10*time.Millisecond, time.Second)
+
+	select {
+	case err := <-w.Errors():
+		assert.ErrorIs(t, err, ErrLockNotHold)
+	case <-time.After(time.Second):
+		t.Fatal("续约失败没有被投递到 Errors()")
+	}
+
+	select {
+	case <-w.Done():
+	case <-time.After(time.Second):
+		t.Fatal("非超时错误应该终止续约循环")
+	}
+}